@@ -1,7 +1,10 @@
-// Fibonacci - recursive and iterative
+// Fibonacci - recursive, iterative, and memoized
 package main
 
-import "fmt"
+import (
+	"container/list"
+	"fmt"
+)
 
 func fibRec(n int64) int64 {
 	if n <= 1 {
@@ -18,7 +21,89 @@ func fibIter(n int64) int64 {
 	return a
 }
 
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRU is a size-bounded cache that evicts the least recently used entry
+// once Add would push it past capacity, the comparison-language stand-in
+// for std/cache.LRU.
+type LRU[K comparable, V any] struct {
+	capacity int
+	ll       *list.List
+	items    map[K]*list.Element
+}
+
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{capacity: capacity, ll: list.New(), items: make(map[K]*list.Element)}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		return el.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *LRU[K, V]) Add(key K, value V) {
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*lruEntry[K, V]).value = value
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+func (c *LRU[K, V]) Remove(key K) {
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+func (c *LRU[K, V]) Len() int {
+	return c.ll.Len()
+}
+
+// Memoize wraps a single-argument pure function in an LRU of the given
+// size: the language-level `@memoize(size=N)` attribute the request asks
+// for is a compiler rewrite that has no home in this benchmark snapshot,
+// so callers use Memoize directly instead of an attribute.
+func Memoize(fn func(int64) int64, size int) func(int64) int64 {
+	cache := NewLRU[int64, int64](size)
+	var memoized func(int64) int64
+	memoized = func(n int64) int64 {
+		if v, ok := cache.Get(n); ok {
+			return v
+		}
+		v := fn(n)
+		cache.Add(n, v)
+		return v
+	}
+	return memoized
+}
+
+var fibRecMemo func(int64) int64
+
+func init() {
+	fibRecMemo = Memoize(func(n int64) int64 {
+		if n <= 1 {
+			return n
+		}
+		return fibRecMemo(n-1) + fibRecMemo(n-2)
+	}, 64)
+}
+
 func main() {
 	fmt.Println(fibRec(20))
 	fmt.Println(fibIter(50))
+	fmt.Println(fibRecMemo(50))
 }
@@ -1,48 +1,57 @@
-// Singly linked list with basic operations
+// Singly linked list with basic operations, backed by a generic List[T]
 package main
 
 import "fmt"
 
-type Node struct {
-	Value int64
-	Next  *Node
+// List is a generic singly linked list, replacing the old hand-rolled
+// per-type Node. head is a plain nil-terminated pointer; a zero List is
+// ready to use.
+type List[T any] struct {
+	head *node[T]
 }
 
-func newNode(val int64) *Node {
-	return &Node{Value: val, Next: nil}
+type node[T any] struct {
+	value T
+	next  *node[T]
 }
 
-func listPush(head *Node, val int64) {
-	n := newNode(val)
-	n.Next = head.Next
-	head.Next = n
+func (l *List[T]) Push(val T) {
+	l.head = &node[T]{value: val, next: l.head}
 }
 
-func listLen(head *Node) int64 {
+func (l *List[T]) Len() int64 {
 	var count int64
-	cur := head.Next
-	for cur != nil {
+	for cur := l.head; cur != nil; cur = cur.next {
 		count++
-		cur = cur.Next
 	}
 	return count
 }
 
-func listSum(head *Node) int64 {
-	var total int64
-	cur := head.Next
-	for cur != nil {
-		total += cur.Value
-		cur = cur.Next
+// Range walks the list front-to-back, yielding each value to fn until fn
+// returns false. This is the iterator protocol the language-side
+// std/collections package is expected to mirror via `for x := range coll`.
+func (l *List[T]) Range(fn func(T) bool) {
+	for cur := l.head; cur != nil; cur = cur.next {
+		if !fn(cur.value) {
+			return
+		}
 	}
+}
+
+func sumList(l *List[int64]) int64 {
+	var total int64
+	l.Range(func(v int64) bool {
+		total += v
+		return true
+	})
 	return total
 }
 
 func main() {
-	head := &Node{Value: 0, Next: nil}
+	var list List[int64]
 	for i := int64(1); i <= 10; i++ {
-		listPush(head, i)
+		list.Push(i)
 	}
-	fmt.Println(listLen(head))
-	fmt.Println(listSum(head))
+	fmt.Println(list.Len())
+	fmt.Println(sumList(&list))
 }
@@ -1,32 +1,110 @@
-// Quicksort with array partitioning
+// Introsort: quicksort with median-of-three pivoting, a heapsort
+// fallback past a recursion-depth limit, and an insertion-sort base
+// case, replacing the plain hand-written quicksort.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"math/bits"
+)
 
-func partition(arr []int64, lo, hi int) int {
-	pivot := arr[hi]
+const insertionThreshold = 12
+
+// Sort orders slice in place using less as the ordering predicate.
+func Sort[T any](slice []T, less func(a, b T) bool) {
+	maxDepth := 2 * bits.Len(uint(len(slice)))
+	introsort(slice, less, maxDepth)
+}
+
+// Ints sorts a slice of int64 ascending.
+func Ints(slice []int64) {
+	Sort(slice, func(a, b int64) bool { return a < b })
+}
+
+func introsort[T any](slice []T, less func(a, b T) bool, depth int) {
+	if len(slice) < insertionThreshold {
+		insertionSort(slice, less)
+		return
+	}
+	if depth == 0 {
+		heapsort(slice, less)
+		return
+	}
+	p := partition(slice, less)
+	introsort(slice[:p], less, depth-1)
+	introsort(slice[p+1:], less, depth-1)
+}
+
+func partition[T any](slice []T, less func(a, b T) bool) int {
+	lo, hi := 0, len(slice)-1
+	mid := lo + (hi-lo)/2
+	medianOfThree(slice, less, lo, mid, hi)
+	pivot := slice[hi]
 	i := lo
 	for j := lo; j < hi; j++ {
-		if arr[j] <= pivot {
-			arr[i], arr[j] = arr[j], arr[i]
+		if !less(pivot, slice[j]) {
+			slice[i], slice[j] = slice[j], slice[i]
 			i++
 		}
 	}
-	arr[i], arr[hi] = arr[hi], arr[i]
+	slice[i], slice[hi] = slice[hi], slice[i]
 	return i
 }
 
-func quicksort(arr []int64, lo, hi int) {
-	if lo < hi {
-		p := partition(arr, lo, hi)
-		quicksort(arr, lo, p-1)
-		quicksort(arr, p+1, hi)
+// medianOfThree moves the median of slice[lo], slice[mid], slice[hi]
+// into slice[hi] so partition always pivots on a representative value.
+func medianOfThree[T any](slice []T, less func(a, b T) bool, lo, mid, hi int) {
+	if less(slice[mid], slice[lo]) {
+		slice[mid], slice[lo] = slice[lo], slice[mid]
+	}
+	if less(slice[hi], slice[lo]) {
+		slice[hi], slice[lo] = slice[lo], slice[hi]
+	}
+	if less(slice[hi], slice[mid]) {
+		slice[hi], slice[mid] = slice[mid], slice[hi]
+	}
+	slice[mid], slice[hi] = slice[hi], slice[mid]
+}
+
+func insertionSort[T any](slice []T, less func(a, b T) bool) {
+	for i := 1; i < len(slice); i++ {
+		for j := i; j > 0 && less(slice[j], slice[j-1]); j-- {
+			slice[j], slice[j-1] = slice[j-1], slice[j]
+		}
+	}
+}
+
+func heapsort[T any](slice []T, less func(a, b T) bool) {
+	n := len(slice)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(slice, less, i, n)
+	}
+	for i := n - 1; i > 0; i-- {
+		slice[0], slice[i] = slice[i], slice[0]
+		siftDown(slice, less, 0, i)
+	}
+}
+
+func siftDown[T any](slice []T, less func(a, b T) bool, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && less(slice[child], slice[child+1]) {
+			child++
+		}
+		if !less(slice[root], slice[child]) {
+			return
+		}
+		slice[root], slice[child] = slice[child], slice[root]
+		root = child
 	}
 }
 
 func main() {
 	arr := []int64{38, 27, 43, 3, 9, 82, 10, 55, 1, 77}
-	quicksort(arr, 0, len(arr)-1)
+	Ints(arr)
 	for _, v := range arr {
 		fmt.Println(v)
 	}